@@ -16,23 +16,24 @@ def main():
     with open(new_config_file, 'r') as file:
         new_config = json.load(file)
 
+    ok = True
     with open(kernel_config_file, 'r') as file:
         lines = file.readlines()
-        # if config_name is not in the file, add it
+        # report every config that did not end up with the requested value
         for config_name, config_value in new_config.items():
             exists = False
             for i, line in enumerate(lines):
                 if line.split('=')[0] == config_name:
                     if "=".join(line.split('=')[1:]).strip() != config_value:
                         print(f"Warning: Config {config_name} is not set to {config_value}")
-                        return False
+                        ok = False
                     exists = True
             if not exists and config_value == "y":
                 print(f"Warning: Config {config_name} is not set to {config_value}")
-                return False
+                ok = False
 
-    return True
+    return ok
 
 
 if __name__ == "__main__":
-    main()
\ No newline at end of file
+    sys.exit(0 if main() else 1)
@@ -59,3 +59,12 @@ else
     echo "Kernel is not built"
     exit 1
 fi
+
+# record build metadata next to the kernel so crash logs can be attributed to this build
+cat > $KERNEL/build-info <<EOF
+commit=$(git describe --always --dirty --abbrev=40)
+diff_sha256=$(git diff HEAD | sha256sum | cut -d' ' -f1)
+release=$(make -s kernelrelease)
+config_sha256=$(sha256sum .config | cut -d' ' -f1)
+compiler=$(sed -n 's/^CONFIG_CC_VERSION_TEXT="\(.*\)"$/\1/p' .config)
+EOF
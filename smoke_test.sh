@@ -0,0 +1,106 @@
+#!/usr/bin/env bash
+# Boot one VM with the built kernel and image, run a trivial command over ssh
+# and check the console for crashes.
+# Example: ./smoke_test.sh
+# option: NUMA_NODES=2 ./smoke_test.sh to split CPUs and memory into NUMA nodes
+# option: PORT=10022 ./smoke_test.sh if a manually started VM already uses 10021
+# option: EXTRA_CMDLINE="isolcpus=1 nohz_full=1 rcu_nocbs=1" ./smoke_test.sh to add boot parameters
+set -eux
+
+# load config/.env
+source config/.env
+
+# stall thresholds, kernel defaults unless config/.env sets them
+RCU_STALL_TIMEOUT=${RCU_STALL_TIMEOUT:-21}
+HUNG_TASK_TIMEOUT=${HUNG_TASK_TIMEOUT:-120}
+WATCHDOG_THRESH=${WATCHDOG_THRESH:-10}
+
+PORT=${PORT:-10021}
+MEM=2048
+CPUS=2
+NUMA_NODES=${NUMA_NODES:-1}
+EXTRA_CMDLINE=${EXTRA_CMDLINE:-}
+OUT=$(mktemp -d)
+LOG=$OUT/vm.log
+SSH="ssh -i $IMAGE/bullseye.id_rsa -p $PORT -o StrictHostKeyChecking=no -o UserKnownHostsFile=/dev/null -o ConnectTimeout=5 -o BatchMode=yes -o ServerAliveInterval=5 -o ServerAliveCountMax=3 root@127.0.0.1"
+
+if [ ! -f $KERNEL/arch/x86/boot/bzImage ] || [ ! -f $IMAGE/bullseye.img ]; then
+    echo "Kernel or image is missing, run ./setup_kernel.sh and ./setup_image.sh first"
+    exit 1
+fi
+
+# split CPUs and memory evenly between NUMA nodes so NUMA balancing has something to do
+NUMA_ARGS=""
+if [ $NUMA_NODES -gt 1 ]; then
+    if [ $((CPUS % NUMA_NODES)) -ne 0 ] || [ $((MEM % NUMA_NODES)) -ne 0 ]; then
+        echo "NUMA_NODES=$NUMA_NODES does not evenly divide $CPUS CPUs and ${MEM}M memory"
+        exit 1
+    fi
+    per_cpus=$((CPUS / NUMA_NODES))
+    for n in $(seq 0 $((NUMA_NODES - 1))); do
+        NUMA_ARGS="$NUMA_ARGS -object memory-backend-ram,id=mem$n,size=$((MEM / NUMA_NODES))M"
+        NUMA_ARGS="$NUMA_ARGS -numa node,nodeid=$n,cpus=$((n * per_cpus))-$(((n + 1) * per_cpus - 1)),memdev=mem$n"
+    done
+fi
+
+STALL_ARGS="rcupdate.rcu_cpu_stall_timeout=$RCU_STALL_TIMEOUT sysctl.kernel.hung_task_timeout_secs=$HUNG_TASK_TIMEOUT watchdog_thresh=$WATCHDOG_THRESH"
+
+start=$(date +%s)
+
+qemu-system-x86_64 \
+	-m ${MEM}M \
+	-smp $CPUS \
+	$NUMA_ARGS \
+	-kernel $KERNEL/arch/x86/boot/bzImage \
+	-append "console=ttyS0 root=/dev/sda earlyprintk=serial net.ifnames=0 $STALL_ARGS $EXTRA_CMDLINE" \
+	-drive file=$IMAGE/bullseye.img,format=raw,snapshot=on \
+	-net user,host=10.0.2.10,hostfwd=tcp:127.0.0.1:$PORT-:22 \
+	-net nic,model=e1000 \
+	-enable-kvm \
+	-nographic \
+	-pidfile $OUT/vm.pid \
+	< /dev/null > $LOG 2>&1 &
+QEMU_PID=$!
+trap 'kill $QEMU_PID 2>/dev/null || true' EXIT
+
+# wait up to 5 minutes for ssh
+booted=0
+deadline=$((start + 300))
+while [ $(date +%s) -lt $deadline ]; do
+    if ! kill -0 $QEMU_PID 2>/dev/null; then
+        echo "qemu exited early, last console output:"
+        tail -n 20 $LOG
+        break
+    fi
+    if $SSH true 2>/dev/null; then
+        booted=1
+        break
+    fi
+    sleep 5
+done
+boot_time=$(($(date +%s) - start))
+
+result=PASS
+run_time=0
+if [ $booted -eq 1 ]; then
+    start=$(date +%s)
+    $SSH "uname -a && cat /proc/loadavg" || result=FAIL
+    # sysctl.* on the command line needs 5.8+, show what the guest actually uses
+    $SSH "echo hung_task_timeout_secs=\$(cat /proc/sys/kernel/hung_task_timeout_secs)" || true
+    run_time=$(($(date +%s) - start))
+else
+    echo "VM did not come up"
+    result=FAIL
+fi
+
+if grep -E "Kernel panic|BUG:|kernel BUG at|Oops|general protection fault|KASAN:|UBSAN:|WARNING:|INFO: task .* blocked|(rcu: )?INFO: rcu_.* (self-)?detected stall|soft lockup|hard LOCKUP" $LOG; then
+    echo "Crash found in console output"
+    result=FAIL
+fi
+
+echo "Smoke test: $result (boot ${boot_time}s, run ${run_time}s, console log $LOG)"
+echo "Stall thresholds: $STALL_ARGS"
+echo "Extra boot parameters: $EXTRA_CMDLINE"
+if [ $result != PASS ]; then
+    exit 1
+fi